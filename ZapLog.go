@@ -2,10 +2,10 @@ package logger
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/natefinch/lumberjack"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"io"
@@ -19,22 +19,22 @@ import (
 
 var SugarLogger *zap.SugaredLogger
 
-func InitLogger(filename string, maxSize, maxBackups, maxAge int, compress bool) {
-	// 确保目录存在且具有正确的权限
-	if err := ensureDir(filename); err != nil {
-		SugarLogger.Errorf("日志文件创建失败: %v", err)
-	}
+// Logger 是未加糖的 *zap.Logger，供需要类型化字段（typed fields）和
+// 请求态子 logger（见 FromContext）的场景使用。
+var Logger *zap.Logger
 
-	writeSyncer := getLogWriter(filename, maxSize, maxBackups, maxAge, compress)
-	consoleWriteSyncer := zapcore.AddSync(os.Stdout)
-	encoder := getEncoder()
-	// 创建分级写入器（Tee），日志将同时写入文件和控制台
-	core := zapcore.NewTee(
-		zapcore.NewCore(encoder, writeSyncer, zapcore.DebugLevel),
-		zapcore.NewCore(encoder, consoleWriteSyncer, zapcore.DebugLevel),
-	)
-	logger := zap.New(core, zap.AddCaller())
-	SugarLogger = logger.Sugar()
+// InitLogger 保留旧签名以兼容历史调用方，内部委托给 InitLoggerWithConfig，
+// 格式固定为 console、文件与控制台均为 DebugLevel。
+func InitLogger(filename string, maxSize, maxBackups, maxAge int, compress bool) {
+	InitLoggerWithConfig(Config{
+		Filename:   filename,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
+		Compress:   compress,
+		Format:     "console",
+		Level:      "debug",
+	})
 }
 
 func ensureDir(filePath string) error {
@@ -45,13 +45,6 @@ func ensureDir(filePath string) error {
 	return nil
 }
 
-func getEncoder() zapcore.Encoder {
-	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
-	return zapcore.NewConsoleEncoder(encoderConfig)
-}
-
 func getLogWriter(filename string, maxSize, maxBackups, maxAge int, compress bool) zapcore.WriteSyncer {
 	lumberJackLogger := &lumberjack.Logger{
 		Filename:   filename,   //文件路径
@@ -60,12 +53,33 @@ func getLogWriter(filename string, maxSize, maxBackups, maxAge int, compress boo
 		MaxAge:     maxAge,     //旧文件最多保存的天数
 		Compress:   compress,   //是否压缩
 	}
+	// 登记到 lumberjackLoggers，供 Rotate（见 lifecycle.go）统一触发滚动
+	lumberjackMu.Lock()
+	lumberjackLoggers = append(lumberjackLoggers, lumberJackLogger)
+	lumberjackMu.Unlock()
 
 	return zapcore.AddSync(lumberJackLogger)
 }
 
-// HttpLogger 请求日志切面
-func HttpLogger() gin.HandlerFunc {
+// HttpLogger 请求日志切面，body 采集不做任何裁剪/脱敏，等价于
+// HttpLoggerWithPolicy(BodyLogPolicy{})。opts 可用 WithOTel 等开启可选能力。
+func HttpLogger(opts ...Option) gin.HandlerFunc {
+	return HttpLoggerWithPolicy(BodyLogPolicy{}, opts...)
+}
+
+// HttpLoggerWithPolicy 同 HttpLogger，但按 policy 限制请求/响应 body 的采集大小、
+// 跳过指定路径或 content type，并对命中的 JSON 字段做脱敏，避免大文件上传/下载
+// 拖垮内存或把密码、token 等敏感信息写进日志。
+func HttpLoggerWithPolicy(policy BodyLogPolicy, opts ...Option) gin.HandlerFunc {
+	options := &httpLoggerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	var tracer oteltrace.Tracer
+	if options.tracerProvider != nil {
+		tracer = options.tracerProvider.Tracer(tracerName)
+	}
+
 	return func(c *gin.Context) {
 		// 开始计时
 		start := time.Now()
@@ -76,25 +90,32 @@ func HttpLogger() gin.HandlerFunc {
 		httpPath, method, clientIP := c.Request.URL.Path, c.Request.Method, c.ClientIP()
 		handlerName := runtime.FuncForPC(reflect.ValueOf(c.Handler()).Pointer()).Name()
 
+		// 路径命中 SkipPaths 对请求、响应两侧都生效；content type 则两侧各自
+		// 按自己的实际类型判断 —— 响应的 content type 往往要到 handler 跑完、
+		// 真正写响应时才知道，不能照搬请求侧的判断结果。
+		pathSkipped := policy.shouldSkipPath(httpPath)
+		reqSkip := pathSkipped || policy.shouldSkipContentType(c.ContentType())
+
 		// 解析请求参数
 		var reqParams interface{}
-		switch c.ContentType() {
-		case "application/json":
+		switch {
+		case reqSkip:
+			reqParams = "[BODY CAPTURE SKIPPED]"
+		case c.ContentType() == "application/json":
 			bodyBytes, _ := io.ReadAll(c.Request.Body)
 			if len(bodyBytes) > 0 {
-				var m map[string]interface{}
-				if err := json.Unmarshal(bodyBytes, &m); err == nil {
-					if marshal, err := json.Marshal(m); err == nil {
-						reqParams = string(marshal)
-					}
-				}
+				redacted := redactJSONFields(bodyBytes, policy.RedactJSONFields)
+				reqParams = truncateBody(redacted, policy.MaxRequestBytes)
 			}
 			// 恢复 Body
 			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		case "application/x-www-form-urlencoded", "multipart/form-data":
+		case c.ContentType() == "application/x-www-form-urlencoded" || c.ContentType() == "multipart/form-data":
 			_ = c.Request.ParseForm()
 			reqParams = c.Request.Form
-		case "application/octet-stream":
+			if encoded := c.Request.Form.Encode(); policy.MaxRequestBytes > 0 && len(encoded) > policy.MaxRequestBytes {
+				reqParams = truncateBody([]byte(encoded), policy.MaxRequestBytes)
+			}
+		case c.ContentType() == "application/octet-stream":
 			reqParams = "[BINARY DATA]"
 		default:
 			// 处理 GET 请求的 query 参数
@@ -105,20 +126,59 @@ func HttpLogger() gin.HandlerFunc {
 			}
 		}
 
-		// 记录请求日志
-		requestLog := fmt.Sprintf(
-			"X-Request-Id:%s\n"+
-				"---------------------------请求开始-----------------------------\n"+
-				"CLASS METHOD: %s\n"+
-				"请求地址: %s\n"+
-				"HTTP METHOD: %s\n"+
-				"请求参数: %v\n"+
-				"IP: %s",
-			requestId, handlerName, httpPath, method, reqParams, clientIP,
+		// 解析上游传入的 traceparent/tracestate，没有或不合法则生成一个新的
+		// traceparent；合法时把它转换成 remote SpanContext 注入请求上下文，
+		// 让 tracer.Start 延续同一条 trace 而不是另起一个 trace root
+		tc, ok := parseTraceparent(c.Request.Header.Get(traceparentHeader))
+		tc.TraceState = c.Request.Header.Get(tracestateHeader)
+		if !ok {
+			tc = newTraceparent()
+		}
+
+		var span oteltrace.Span
+		if tracer != nil {
+			ctx := c.Request.Context()
+			if ok {
+				if remoteSC, valid := remoteSpanContext(tc); valid {
+					ctx = oteltrace.ContextWithSpanContext(ctx, remoteSC)
+				}
+			}
+			ctx, span = tracer.Start(ctx, httpPath, oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+			c.Request = c.Request.WithContext(ctx)
+			sc := span.SpanContext()
+			tc.TraceID = sc.TraceID().String()
+			tc.SpanID = sc.SpanID().String()
+			if sc.IsSampled() {
+				tc.TraceFlags = "01"
+			} else {
+				tc.TraceFlags = "00"
+			}
+			if ts := sc.TraceState().String(); ts != "" {
+				tc.TraceState = ts
+			}
+		}
+
+		// tc 到这里才最终确定（可能已被 span 的真实 id 覆盖），再回写响应头，
+		// 保证客户端拿到的 traceparent/tracestate 与日志、span 里的完全一致
+		c.Header(traceparentHeader, tc.header())
+		if tc.TraceState != "" {
+			c.Header(tracestateHeader, tc.TraceState)
+		}
+
+		// 绑定请求态 logger，业务 handler 可通过 FromContext 取出，
+		// 使同一请求内的所有日志都带上 request_id 及 trace 字段
+		childLogger := Logger.With(
+			zap.String("request_id", requestId),
+			zap.String("trace_id", tc.TraceID),
+			zap.String("span_id", tc.SpanID),
+			zap.String("trace_flags", tc.TraceFlags),
+			zap.String("trace_state", tc.TraceState),
 		)
+		c.Set(loggerContextKey, childLogger)
 
-		// 设置响应记录器
-		recorder := NewResponseRecorder(c.Writer)
+		// 设置响应记录器：只把最多 MaxResponseBytes 字节缓存下来供日志使用，
+		// 其余字节仍然原样写给客户端，不会因为记日志而把整个响应都驻留内存
+		recorder := newCappedResponseWriter(c.Writer, policy.MaxResponseBytes, pathSkipped, policy.SkipContentTypes)
 		c.Writer = recorder
 
 		// 调用后续处理
@@ -128,31 +188,54 @@ func HttpLogger() gin.HandlerFunc {
 		statusCode := recorder.Status()
 		errorMessage := c.Errors.ByType(gin.ErrorTypePrivate).String()
 
+		if span != nil {
+			recordSpanResult(span, statusCode)
+		}
+
 		// 解析响应数据
 		respCT := strings.SplitN(recorder.Header().Get("Content-Type"), ";", 2)[0]
+		respSkip := pathSkipped || policy.shouldSkipContentType(respCT)
 		var responseData string
-		switch respCT {
-		case "application/json":
-			responseData = recorder.Body.String()
-		case "application/x-www-form-urlencoded", "multipart/form-data":
-			responseData = recorder.Body.String()
-		case "audio/mpeg", "text/html", "application/octet-stream":
+		switch {
+		case respSkip:
+			responseData = "[BODY CAPTURE SKIPPED]"
+		case respCT == "application/json":
+			if dropped := recorder.DroppedBytes(); dropped > 0 {
+				// recorder.Body() 在这里只是一段被 maxBytes 截断的 JSON 前缀，已经不是
+				// 合法 JSON，redactJSONFields 解析失败只能原样返回 —— 敏感字段就会
+				// 明文进日志。截断场景下不再尝试脱敏后拼接，直接给占位符。
+				responseData = fmt.Sprintf("[REDACTED BODY — truncated, %d bytes dropped]", dropped)
+			} else {
+				body := redactJSONFields(recorder.Body().Bytes(), policy.RedactJSONFields)
+				responseData = string(body)
+			}
+		case respCT == "application/x-www-form-urlencoded" || respCT == "multipart/form-data":
+			responseData = withTruncationMarker(recorder.Body().String(), recorder.DroppedBytes())
+		case respCT == "audio/mpeg" || respCT == "text/html" || respCT == "application/octet-stream":
 			responseData = "[BINARY DATA]"
 		default:
 			responseData = fmt.Sprintf("[UNSUPPORTED CONTENT TYPE: %s]", respCT)
 		}
 
 		cost := time.Since(start)
-		responseLog := fmt.Sprintf(
-			"X-Request-Id:%s\n"+
-				"HTTP STATUS: %d\n"+
-				"Error Messages: %s\n"+
-				"响应数据: %s\n"+
-				"响应大小: %d\n"+
-				"耗时: %s\n"+
-				"---------------------------请求结束-----------------------------",
-			requestId, statusCode, errorMessage, responseData, recorder.Body.Len(), cost,
-		)
-		SugarLogger.Infof("%s\n%s", requestLog, responseLog)
+
+		// request_id 及 trace_id/span_id/trace_flags/trace_state 已经通过
+		// childLogger.With（见上方）绑定，这里不再重复传，避免 JSON 输出里
+		// 同一个 key 出现两次
+		fields := []zap.Field{
+			zap.String("method", method),
+			zap.String("path", httpPath),
+			zap.String("client_ip", clientIP),
+			zap.Int("status", statusCode),
+			zap.Duration("latency", cost),
+			zap.Int("resp_size", recorder.Size()),
+			zap.String("handler", handlerName),
+			zap.Any("req", reqParams),
+			zap.Any("resp", responseData),
+		}
+		if errorMessage != "" {
+			fields = append(fields, zap.String("error", errorMessage))
+		}
+		childLogger.Info("http request", fields...)
 	}
 }
@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+// AsyncWriterConfig 控制异步缓冲写入器的批量行为。
+type AsyncWriterConfig struct {
+	BufferSize    int           // channel 可缓冲的日志条数，<=0 时使用默认值
+	FlushInterval time.Duration // 定时 flush 的间隔，<=0 时使用默认值
+}
+
+const (
+	defaultAsyncBufferSize    = 1024
+	defaultAsyncFlushInterval = time.Second
+)
+
+// asyncWriteSyncer 把磁盘 I/O 从请求处理路径上挪走：Write 只是把数据塞进
+// 一个 channel，真正的落盘由后台 goroutine 按"攒够一批或到达 flush 间隔"
+// 的策略批量执行，避免高 QPS 下每条日志都触发一次同步写盘。
+type asyncWriteSyncer struct {
+	target    zapcore.WriteSyncer
+	queue     chan []byte
+	flushReq  chan chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newAsyncWriteSyncer(target zapcore.WriteSyncer, cfg AsyncWriterConfig) *asyncWriteSyncer {
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultAsyncBufferSize
+	}
+	interval := cfg.FlushInterval
+	if interval <= 0 {
+		interval = defaultAsyncFlushInterval
+	}
+
+	a := &asyncWriteSyncer{
+		target:   target,
+		queue:    make(chan []byte, bufferSize),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	a.wg.Add(1)
+	go a.loop(interval, bufferSize)
+	return a
+}
+
+// Write 实现 zapcore.WriteSyncer；写入的是 p 的拷贝，因为 zapcore 在调用
+// 返回后可能复用底层缓冲区。
+func (a *asyncWriteSyncer) Write(p []byte) (int, error) {
+	buf := make([]byte, len(p))
+	copy(buf, p)
+	select {
+	case a.queue <- buf:
+	case <-a.done:
+		// 已经在关闭流程中，丢弃新写入而不是阻塞调用方
+		return len(p), nil
+	}
+	return len(p), nil
+}
+
+// Sync 强制后台 goroutine 把 queue 中已入队但还未落盘的日志立即 flush 一遍，
+// 再透传给底层 writer，因此在异步 sink 开启时调用 Logger.Sync()（例如沿用
+// 旧代码里常见的 defer Logger.Sync()）也不会丢日志。注意高频调用 Sync 会
+// 打断"攒够一批再写盘"的节奏、抵消批量写入带来的性能收益，进程退出前的
+// 收尾仍应优先用 Close()。
+func (a *asyncWriteSyncer) Sync() error {
+	done := make(chan struct{})
+	select {
+	case a.flushReq <- done:
+		<-done
+	case <-a.done:
+		// 已经在关闭流程中，Close 会负责把剩余数据落盘
+	}
+	return a.target.Sync()
+}
+
+// Close 通知后台 goroutine 把 queue 中剩余日志全部落盘后退出，保证进程
+// 关闭前不丢日志。可安全重复调用（例如 SignalHandler 和调用方的 defer
+// Close() 都触发了一次），只有第一次调用真正生效。
+func (a *asyncWriteSyncer) Close() error {
+	a.closeOnce.Do(func() {
+		close(a.done)
+		a.wg.Wait()
+		a.closeErr = a.target.Sync()
+	})
+	return a.closeErr
+}
+
+func (a *asyncWriteSyncer) loop(interval time.Duration, bufferSize int) {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, bufferSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		for _, b := range batch {
+			_, _ = a.target.Write(b)
+		}
+		_ = a.target.Sync()
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case b := <-a.queue:
+			batch = append(batch, b)
+			if len(batch) >= bufferSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case req := <-a.flushReq:
+		drainPending:
+			for {
+				select {
+				case b := <-a.queue:
+					batch = append(batch, b)
+				default:
+					break drainPending
+				}
+			}
+			flush()
+			close(req)
+		case <-a.done:
+			for {
+				select {
+				case b := <-a.queue:
+					batch = append(batch, b)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
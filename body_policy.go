@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BodyLogPolicy 控制 HttpLoggerWithPolicy 对请求/响应体的采集方式，用于避免
+// 把敏感字段（密码、token 等）写进日志，以及把日志里记录的 body 大小控制在
+// MaxRequestBytes/MaxResponseBytes 以内。
+//
+// 响应体的截断是真正起作用的内存保护：cappedResponseWriter（见
+// capped_response_writer.go）只会为日志缓存最多 MaxResponseBytes 字节，
+// 超出部分直接丢弃，不会为了记日志而把整个大文件响应都驻留在内存里。
+// 请求体则不同 —— 为了在读取后把 Body 完整地还给下游 handler，
+// io.ReadAll 仍需要先读入全量请求体，MaxRequestBytes 只裁剪最终写进日志的
+// 字符串；如果需要真正限制请求体大小，应在更前置的地方用
+// http.MaxBytesReader 一类的机制兜底，而不是依赖这里的日志中间件。
+type BodyLogPolicy struct {
+	MaxRequestBytes  int      // 请求体最多记录的字节数，<=0 表示不限制
+	MaxResponseBytes int      // 响应体最多记录的字节数，<=0 表示不限制
+	SkipPaths        []string // 命中任一正则即跳过该请求的 body 采集
+	SkipContentTypes []string // 命中任一 content type 即跳过 body 采集
+	RedactJSONFields []string // 命中的 JSON 字段（不区分大小写）会被替换为 "[REDACTED]"
+}
+
+const redactedPlaceholder = "[REDACTED]"
+
+// shouldSkipPath 判断 httpPath 是否命中 SkipPaths 中的任一正则。
+func (p BodyLogPolicy) shouldSkipPath(httpPath string) bool {
+	for _, pattern := range p.SkipPaths {
+		if matched, err := regexp.MatchString(pattern, httpPath); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldSkipContentType 判断 contentType 是否命中 SkipContentTypes。
+func (p BodyLogPolicy) shouldSkipContentType(contentType string) bool {
+	for _, ct := range p.SkipContentTypes {
+		if strings.EqualFold(ct, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateBody 在超过 maxBytes 时截断 body 并附加标记；maxBytes<=0 表示不截断。
+func truncateBody(body []byte, maxBytes int) string {
+	if maxBytes <= 0 || len(body) <= maxBytes {
+		return string(body)
+	}
+	return withTruncationMarker(string(body[:maxBytes]), len(body)-maxBytes)
+}
+
+// withTruncationMarker 在 dropped>0 时给 body 追加 "…[truncated N bytes]" 标记，
+// 供 cappedResponseWriter 这类"已经在写入阶段截断"的场景复用同一种标记格式。
+func withTruncationMarker(body string, dropped int) string {
+	if dropped <= 0 {
+		return body
+	}
+	return fmt.Sprintf("%s…[truncated %d bytes]", body, dropped)
+}
+
+// redactJSONFields 解析 body 为 JSON 并递归屏蔽 fields 命中的字段（大小写不敏感），
+// 解析失败时原样返回，保证非 JSON body 不受影响。
+func redactJSONFields(body []byte, fields []string) []byte {
+	if len(fields) == 0 {
+		return body
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body
+	}
+	redactValue(data, fields)
+	redacted, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue 递归遍历 map/slice，命中 fields 的 key 用占位符替换其值。
+func redactValue(v interface{}, fields []string) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, sub := range value {
+			if matchesField(key, fields) {
+				value[key] = redactedPlaceholder
+				continue
+			}
+			redactValue(sub, fields)
+		}
+	case []interface{}:
+		for _, item := range value {
+			redactValue(item, fields)
+		}
+	}
+}
+
+func matchesField(key string, fields []string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(key, f) {
+			return true
+		}
+	}
+	return false
+}
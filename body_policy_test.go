@@ -0,0 +1,114 @@
+package logger
+
+import "testing"
+
+func TestTruncateBody(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		maxBytes int
+		want     string
+	}{
+		{"under limit returned as-is", "hello", 10, "hello"},
+		{"exact limit returned as-is", "hello", 5, "hello"},
+		{"over limit truncated with marker", "hello world", 5, "hello…[truncated 6 bytes]"},
+		{"maxBytes<=0 means unlimited", "hello world", 0, "hello world"},
+		{"negative maxBytes means unlimited", "hello world", -1, "hello world"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := truncateBody([]byte(tc.body), tc.maxBytes)
+			if got != tc.want {
+				t.Errorf("truncateBody(%q, %d) = %q, want %q", tc.body, tc.maxBytes, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithTruncationMarker(t *testing.T) {
+	if got := withTruncationMarker("abc", 0); got != "abc" {
+		t.Errorf("dropped=0 should not append a marker, got %q", got)
+	}
+	if got := withTruncationMarker("abc", -1); got != "abc" {
+		t.Errorf("negative dropped should not append a marker, got %q", got)
+	}
+	want := "abc…[truncated 4 bytes]"
+	if got := withTruncationMarker("abc", 4); got != want {
+		t.Errorf("withTruncationMarker(\"abc\", 4) = %q, want %q", got, want)
+	}
+}
+
+func TestRedactJSONFields(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		fields []string
+		want   string
+	}{
+		{
+			name:   "no fields configured returns body unchanged",
+			body:   `{"password":"secret"}`,
+			fields: nil,
+			want:   `{"password":"secret"}`,
+		},
+		{
+			name:   "redacts a top-level field",
+			body:   `{"username":"alice","password":"secret"}`,
+			fields: []string{"password"},
+			want:   `{"password":"[REDACTED]","username":"alice"}`,
+		},
+		{
+			name:   "matches case-insensitively",
+			body:   `{"Authorization":"Bearer xyz"}`,
+			fields: []string{"authorization"},
+			want:   `{"Authorization":"[REDACTED]"}`,
+		},
+		{
+			name:   "redacts nested fields inside objects and arrays",
+			body:   `{"users":[{"token":"a"},{"token":"b"}]}`,
+			fields: []string{"token"},
+			want:   `{"users":[{"token":"[REDACTED]"},{"token":"[REDACTED]"}]}`,
+		},
+		{
+			name:   "malformed JSON is returned unchanged instead of erroring",
+			body:   `not json`,
+			fields: []string{"password"},
+			want:   `not json`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(redactJSONFields([]byte(tc.body), tc.fields))
+			if got != tc.want {
+				t.Errorf("redactJSONFields(%q, %v) = %q, want %q", tc.body, tc.fields, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBodyLogPolicyShouldSkipPath(t *testing.T) {
+	p := BodyLogPolicy{SkipPaths: []string{"^/health$", "^/internal/"}}
+	cases := map[string]bool{
+		"/health":        true,
+		"/internal/ping": true,
+		"/api/users":     false,
+	}
+	for path, want := range cases {
+		if got := p.shouldSkipPath(path); got != want {
+			t.Errorf("shouldSkipPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestBodyLogPolicyShouldSkipContentType(t *testing.T) {
+	p := BodyLogPolicy{SkipContentTypes: []string{"video/mp4", "application/octet-stream"}}
+	if !p.shouldSkipContentType("video/mp4") {
+		t.Error("expected exact match to be skipped")
+	}
+	if !p.shouldSkipContentType("VIDEO/MP4") {
+		t.Error("expected case-insensitive match to be skipped")
+	}
+	if p.shouldSkipContentType("application/json") {
+		t.Error("expected non-matching content type to not be skipped")
+	}
+}
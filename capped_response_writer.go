@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cappedResponseWriter 包装 gin.ResponseWriter，把写入的数据原样透传给真实的
+// 响应流（客户端收到的内容不受影响），同时只在一个上限为 maxBytes 的内存
+// 缓冲区里额外保留一份用于日志输出的拷贝 —— 这样大文件下载不会因为日志
+// 中间件而把整个响应体都驻留在内存里。skipPath 为 true，或响应的
+// Content-Type 命中 skipContentTypes，则完全不保留这份拷贝。
+type cappedResponseWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	maxBytes    int
+	written     int // 经过 capture 的总字节数（含被丢弃的部分），用于计算截断标记
+	skipPath    bool
+	skipTypes   []string
+	typeChecked bool
+	skipByType  bool
+}
+
+func newCappedResponseWriter(w gin.ResponseWriter, maxBytes int, skipPath bool, skipTypes []string) *cappedResponseWriter {
+	return &cappedResponseWriter{ResponseWriter: w, maxBytes: maxBytes, skipPath: skipPath, skipTypes: skipTypes}
+}
+
+func (w *cappedResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.capture(b)
+	return n, err
+}
+
+func (w *cappedResponseWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.capture([]byte(s))
+	return n, err
+}
+
+// capture 把 b 写入内部缓冲区，超过 maxBytes 的部分直接丢弃而不进入内存；
+// maxBytes<=0 表示不限制（与 BodyLogPolicy 其它字段的 "<=0 不生效" 约定一致）。
+func (w *cappedResponseWriter) capture(b []byte) {
+	if w.skipPath || w.skippedByContentType() {
+		return
+	}
+	w.written += len(b)
+	if w.maxBytes <= 0 {
+		w.buf.Write(b)
+		return
+	}
+	remaining := w.maxBytes - w.buf.Len()
+	if remaining <= 0 {
+		return
+	}
+	if len(b) > remaining {
+		b = b[:remaining]
+	}
+	w.buf.Write(b)
+}
+
+// skippedByContentType 只在第一次写入时按响应头判断一次：此时状态行和响应头
+// 已经确定，Content-Type 不会再变。
+func (w *cappedResponseWriter) skippedByContentType() bool {
+	if w.typeChecked {
+		return w.skipByType
+	}
+	w.typeChecked = true
+	ct := strings.SplitN(w.Header().Get("Content-Type"), ";", 2)[0]
+	for _, skip := range w.skipTypes {
+		if strings.EqualFold(skip, ct) {
+			w.skipByType = true
+			break
+		}
+	}
+	return w.skipByType
+}
+
+// Body 返回目前为止保留下来的（可能被截断的）响应体拷贝，供日志记录使用。
+func (w *cappedResponseWriter) Body() *bytes.Buffer {
+	return &w.buf
+}
+
+// DroppedBytes 返回因为超过 maxBytes 而未进入 Body() 的字节数，0 表示未截断。
+func (w *cappedResponseWriter) DroppedBytes() int {
+	if d := w.written - w.buf.Len(); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"time"
+)
+
+// SamplingConfig 对应 zapcore 的采样策略：每秒前 Initial 条全部记录，
+// 之后每 Thereafter 条才记录一条，用于在高 QPS 场景下降低重复日志的开销。
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+}
+
+// Config 描述一次 InitLoggerWithConfig 调用所需的全部参数。
+// Filename/MaxSize/MaxBackups/MaxAge/Compress 沿用 lumberjack 的滚动策略，
+// Format/Level 等字段用于控制编码方式与级别路由。
+type Config struct {
+	Filename   string // 日志文件路径
+	MaxSize    int    // 单个日志文件的最大存储量（单位 MB）
+	MaxBackups int    // 最多保留的文件数量
+	MaxAge     int    // 旧文件最多保存的天数
+	Compress   bool   // 是否压缩
+
+	Format string // "json" 或 "console"，默认为 "console"
+	Level  string // 默认级别，file/console 未单独指定时使用
+
+	FileLevel    string // 文件输出的级别，留空则使用 Level
+	ConsoleLevel string // 控制台输出的级别，留空则使用 Level
+
+	StacktraceKey   string // 堆栈信息字段名，留空则使用 zap 默认值 "stacktrace"
+	StacktraceLevel string // 达到此级别才附加堆栈，留空且 StacktraceKey 非空时默认 "error"
+
+	InitialFields map[string]interface{} // 附加到每条日志的初始字段，如 service、env
+
+	Sampling *SamplingConfig // 为空则不开启采样
+
+	// Sinks 非空时，文件侧改为按 Sinks 的各级别文件名分别落盘（见
+	// SinkBuilder），此时 Filename/MaxSize/MaxBackups/MaxAge/Compress 不再
+	// 用于文件 sink，仅 ensureDir(Filename) 仍会执行；FileLevel 同样不作用于
+	// 这些 sink，各级别文件只按 SinkBuilder 的级别路由接收日志。
+	Sinks *SinkBuilder
+}
+
+// parseLevel 将字符串解析为 zapcore.Level，解析失败时回退到 InfoLevel。
+func parseLevel(level string) zapcore.Level {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return zapcore.InfoLevel
+	}
+	return l
+}
+
+// getEncoderForFormat 按 format 构建对应的 Encoder，json 用于日志采集系统，
+// console 保留人类可读的格式，便于本地调试。
+func getEncoderForFormat(format, stacktraceKey string) zapcore.Encoder {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	if stacktraceKey != "" {
+		encoderConfig.StacktraceKey = stacktraceKey
+	}
+	if format == "json" {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
+
+// InitLoggerWithConfig 按 Config 构建分级、可选 JSON 编码、可选采样的 core，
+// 相比 InitLogger 支持独立配置文件/控制台级别、初始字段与采样策略。
+func InitLoggerWithConfig(cfg Config) {
+	// 重新 init 时丢弃上一次遗留的 lumberjackLoggers/asyncSinks：否则 Rotate/Close
+	// 会继续操作不再挂在当前 Logger 上的旧 writer，而新 sink 又不会被它们管到。
+	// 旧的异步 sink 在丢弃引用前先 Close 一次，避免其后台 goroutine 泄漏。
+	lumberjackMu.Lock()
+	staleAsyncSinks := asyncSinks
+	lumberjackLoggers = nil
+	asyncSinks = nil
+	lumberjackMu.Unlock()
+	for _, async := range staleAsyncSinks {
+		_ = async.Close()
+	}
+
+	if err := ensureDir(cfg.Filename); err != nil {
+		SugarLogger.Errorf("日志文件创建失败: %v", err)
+	}
+
+	format := cfg.Format
+	if format == "" {
+		format = "console"
+	}
+	encoder := getEncoderForFormat(format, cfg.StacktraceKey)
+
+	fileLevel := cfg.FileLevel
+	if fileLevel == "" {
+		fileLevel = cfg.Level
+	}
+	consoleLevel := cfg.ConsoleLevel
+	if consoleLevel == "" {
+		consoleLevel = cfg.Level
+	}
+
+	var fileCore zapcore.Core
+	if cfg.Sinks != nil {
+		fileCore = cfg.Sinks.Build(encoder)
+	} else {
+		writeSyncer := getLogWriter(cfg.Filename, cfg.MaxSize, cfg.MaxBackups, cfg.MaxAge, cfg.Compress)
+		fileCore = zapcore.NewCore(encoder, writeSyncer, parseLevel(fileLevel))
+	}
+	consoleWriteSyncer := zapcore.AddSync(os.Stdout)
+
+	core := zapcore.NewTee(
+		fileCore,
+		zapcore.NewCore(encoder, consoleWriteSyncer, parseLevel(consoleLevel)),
+	)
+
+	if cfg.Sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+	}
+
+	opts := []zap.Option{zap.AddCaller()}
+	if cfg.StacktraceKey != "" || cfg.StacktraceLevel != "" {
+		// encoderConfig.StacktraceKey 只是告诉 encoder 用哪个字段名输出堆栈，
+		// 真正触发 zap 采集堆栈的是 AddStacktrace，两者缺一不可
+		stacktraceLevel := cfg.StacktraceLevel
+		if stacktraceLevel == "" {
+			stacktraceLevel = "error"
+		}
+		opts = append(opts, zap.AddStacktrace(parseLevel(stacktraceLevel)))
+	}
+	if len(cfg.InitialFields) > 0 {
+		fields := make([]zap.Field, 0, len(cfg.InitialFields))
+		for k, v := range cfg.InitialFields {
+			fields = append(fields, zap.Any(k, v))
+		}
+		opts = append(opts, zap.Fields(fields...))
+	}
+
+	zapLogger := zap.New(core, opts...)
+	Logger = zapLogger
+	SugarLogger = zapLogger.Sugar()
+}
@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// loggerContextKey 是 HttpLogger 中间件用来在 gin.Context 上挂载请求态
+// logger 的 key，与 FromContext 配套使用。
+const loggerContextKey = "logger"
+
+// FromContext 取出 HttpLogger 中间件为当前请求绑定的子 logger（已带上
+// request_id 字段），以便业务 handler 输出的日志能按请求关联。若中间件
+// 未设置（例如未经过 HttpLogger 的调用路径），回退到全局 Logger。
+func FromContext(c *gin.Context) *zap.Logger {
+	if v, ok := c.Get(loggerContextKey); ok {
+		if l, ok := v.(*zap.Logger); ok {
+			return l
+		}
+	}
+	return Logger
+}
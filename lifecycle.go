@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"github.com/natefinch/lumberjack"
+	"go.uber.org/multierr"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// lumberjackLoggers 记录 getLogWriter 创建过的所有 lumberjack.Logger，
+// 供 Rotate 统一触发滚动；lumberjackMu 保护它与 asyncSinks（见
+// sink_builder.go）的并发读写，因为 Rotate/Close 可能在 SignalHandler 的
+// 独立 goroutine 里被调用，同时又有 InitLoggerWithConfig 在追加新 sink。
+var (
+	lumberjackLoggers []*lumberjack.Logger
+	lumberjackMu      sync.Mutex
+)
+
+// Close 应在进程退出前调用：先 drain 所有异步缓冲 sink（见 async_writer.go），
+// 再对全局 Logger 调 Sync()。stdout 在部分平台上 Sync 会返回
+// "invalid argument"，这是 zap 的已知遗留问题而非真正的写入失败，这里忽略它，
+// 但不会因此掩盖其他 core（例如文件 sink）报出的真实错误。
+func Close() error {
+	lumberjackMu.Lock()
+	sinks := append([]*asyncWriteSyncer(nil), asyncSinks...)
+	lumberjackMu.Unlock()
+
+	for _, async := range sinks {
+		_ = async.Close()
+	}
+	if Logger == nil {
+		return nil
+	}
+	if err := Logger.Sync(); err != nil && !isIgnorableSyncError(err) {
+		return err
+	}
+	return nil
+}
+
+// isIgnorableSyncError 判断 Sync() 返回的（可能由 multierr 合并而成的）错误
+// 是否全部是已知可忽略的 stdout EINVAL；只要其中一个子错误不是，就整体不忽略。
+func isIgnorableSyncError(err error) bool {
+	for _, e := range multierr.Errors(err) {
+		if !(errors.Is(e, syscall.EINVAL) || strings.Contains(e.Error(), "invalid argument")) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rotate 对所有已创建的 lumberjack.Logger 触发一次滚动，通常配合 SIGHUP
+// 在不重启进程的情况下切出一个新的日志文件。
+func Rotate() error {
+	lumberjackMu.Lock()
+	loggers := append([]*lumberjack.Logger(nil), lumberjackLoggers...)
+	lumberjackMu.Unlock()
+
+	var firstErr error
+	for _, l := range loggers {
+		if err := l.Rotate(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// SignalHandler 订阅 SIGHUP 触发 Rotate()，订阅 SIGTERM/SIGINT 触发
+// drain+Sync 后停止监听并返回，避免容器被杀时有日志还留在内存里没落盘。
+// 它本身不结束进程：收到的这个 SIGTERM/SIGINT 已被它消费，不会再触发默认的退出。
+// ctx 被取消时停止监听并返回。
+func SignalHandler(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigCh:
+			switch sig {
+			case syscall.SIGHUP:
+				if err := Rotate(); err != nil {
+					SugarLogger.Errorf("日志滚动失败: %v", err)
+				}
+			case syscall.SIGTERM, syscall.SIGINT:
+				if err := Close(); err != nil {
+					SugarLogger.Errorf("日志同步失败: %v", err)
+				}
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// tracerName 用作 TracerProvider.Tracer 的 instrumentation name。
+const tracerName = "github.com/soofjan1234/logger"
+
+// httpLoggerOptions 聚合 HttpLogger/HttpLoggerWithPolicy 的可选行为。
+type httpLoggerOptions struct {
+	tracerProvider oteltrace.TracerProvider
+}
+
+// Option 为 HttpLogger/HttpLoggerWithPolicy 附加可选能力，目前只有 WithOTel。
+type Option func(*httpLoggerOptions)
+
+// WithOTel 让中间件为每个请求起一个 server span，记录状态码与耗时，
+// 并把 span 的 trace_id/span_id 对齐到请求态 logger 的字段上，
+// 从而把日志和链路追踪关联起来。
+func WithOTel(tp oteltrace.TracerProvider) Option {
+	return func(o *httpLoggerOptions) {
+		o.tracerProvider = tp
+	}
+}
+
+// recordSpanResult 把响应状态码和耗时记录到 span 上并结束 span。
+func recordSpanResult(span oteltrace.Span, statusCode int) {
+	span.SetAttributes(attribute.Int("http.status_code", statusCode))
+	span.End()
+}
+
+// remoteSpanContext 把上游传入、已解析的 traceContext 转换成一个 remote
+// oteltrace.SpanContext，供 tracer.Start 延续同一条 trace，而不是每次都
+// 起一个新的 trace root。tc 的字段必须是合法十六进制（parseTraceparent 已
+// 校验过），否则返回 ok=false。
+func remoteSpanContext(tc traceContext) (sc oteltrace.SpanContext, ok bool) {
+	traceID, err := oteltrace.TraceIDFromHex(tc.TraceID)
+	if err != nil {
+		return oteltrace.SpanContext{}, false
+	}
+	spanID, err := oteltrace.SpanIDFromHex(tc.SpanID)
+	if err != nil {
+		return oteltrace.SpanContext{}, false
+	}
+	var flags oteltrace.TraceFlags
+	if tc.TraceFlags == "01" {
+		flags = oteltrace.FlagsSampled
+	}
+	traceState, _ := oteltrace.ParseTraceState(tc.TraceState)
+	return oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		TraceState: traceState,
+		Remote:     true,
+	}), true
+}
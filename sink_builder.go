@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// asyncSinks 记录所有开启了异步缓冲的 sink，供 Close（见 lifecycle.go）
+// 在进程退出前统一 drain，避免遗漏未落盘的日志。
+var asyncSinks []*asyncWriteSyncer
+
+// SinkBuilder 把不同级别的日志路由到各自独立的 lumberjack 文件
+// （ErrorFileName/WarnFileName/InfoFileName/DebugFileName），而不是
+// 挤在同一个文件里，便于按级别排查问题、分别设置滚动与保留策略。
+// 为空的文件名表示不为该级别单独建 sink。
+type SinkBuilder struct {
+	ErrorFileName string
+	WarnFileName  string
+	InfoFileName  string
+	DebugFileName string
+
+	MaxSize    int
+	MaxBackups int
+	MaxAge     int
+	Compress   bool
+
+	Async *AsyncWriterConfig // 非空时，每个 sink 都包一层异步缓冲写入
+}
+
+// Build 为配置了文件名的级别各构建一个只接收该级别日志的 zapcore.Core
+// （Error sink 例外，同时接收更高的级别，见下），再用 zapcore.NewTee 组合起来。
+func (b SinkBuilder) Build(encoder zapcore.Encoder) zapcore.Core {
+	var cores []zapcore.Core
+
+	addSink := func(filename string, enabler zap.LevelEnablerFunc) {
+		if filename == "" {
+			return
+		}
+		writeSyncer := getLogWriter(filename, b.MaxSize, b.MaxBackups, b.MaxAge, b.Compress)
+		if b.Async != nil {
+			async := newAsyncWriteSyncer(writeSyncer, *b.Async)
+			lumberjackMu.Lock()
+			asyncSinks = append(asyncSinks, async)
+			lumberjackMu.Unlock()
+			writeSyncer = async
+		}
+		cores = append(cores, zapcore.NewCore(encoder, writeSyncer, enabler))
+	}
+
+	// Error sink 用 >=ErrorLevel 而不是精确匹配：Fatal/DPanic/Panic 理应比
+	// Error 更值得落盘，精确匹配会让它们不命中任何文件 sink，只剩 stdout，
+	// 崩溃现场最关键的那几行反而没有持久化。Warn/Info/Debug 仍按级别精确分流。
+	addSink(b.ErrorFileName, func(l zapcore.Level) bool { return l >= zapcore.ErrorLevel })
+	addSink(b.WarnFileName, func(l zapcore.Level) bool { return l == zapcore.WarnLevel })
+	addSink(b.InfoFileName, func(l zapcore.Level) bool { return l == zapcore.InfoLevel })
+	addSink(b.DebugFileName, func(l zapcore.Level) bool { return l == zapcore.DebugLevel })
+
+	return zapcore.NewTee(cores...)
+}
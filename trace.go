@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// traceContext 保存一次请求解析/生成出的 W3C Trace Context
+// （https://www.w3.org/TR/trace-context/）上下文：traceparent 的三段
+// （trace_id/span_id/trace_flags）加上可选的 tracestate 原始值。
+type traceContext struct {
+	TraceID    string
+	SpanID     string
+	TraceFlags string
+	TraceState string
+}
+
+// header 按 "version-trace_id-parent_id-trace_flags" 格式序列化，
+// version 固定为 "00"。
+func (tc traceContext) header() string {
+	return fmt.Sprintf("00-%s-%s-%s", tc.TraceID, tc.SpanID, tc.TraceFlags)
+}
+
+// parseTraceparent 解析形如
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" 的 traceparent，
+// 不合法（段数、长度、非十六进制、全零 id）时返回 ok=false，由调用方生成新的上下文。
+func parseTraceparent(header string) (tc traceContext, ok bool) {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 {
+		return traceContext{}, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 {
+		return traceContext{}, false
+	}
+	if !isHex(version) || !isHex(traceID) || !isHex(spanID) || !isHex(flags) {
+		return traceContext{}, false
+	}
+	if traceID == strings.Repeat("0", 32) || spanID == strings.Repeat("0", 16) {
+		return traceContext{}, false
+	}
+	return traceContext{TraceID: traceID, SpanID: spanID, TraceFlags: flags}, true
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// newTraceparent 在上游未传 traceparent（或传了不合法的值）时生成一个符合
+// 规范的新上下文，并标记为采样（flags="01"）。
+func newTraceparent() traceContext {
+	traceIDBytes := make([]byte, 16)
+	_, _ = rand.Read(traceIDBytes)
+	spanIDBytes := make([]byte, 8)
+	_, _ = rand.Read(spanIDBytes)
+	return traceContext{
+		TraceID:    hex.EncodeToString(traceIDBytes),
+		SpanID:     hex.EncodeToString(spanIDBytes),
+		TraceFlags: "01",
+	}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import "testing"
+
+func TestParseTraceparent(t *testing.T) {
+	const valid = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("valid header parses all segments", func(t *testing.T) {
+		tc, ok := parseTraceparent(valid)
+		if !ok {
+			t.Fatalf("expected %q to parse", valid)
+		}
+		if tc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("TraceID = %q", tc.TraceID)
+		}
+		if tc.SpanID != "00f067aa0ba902b7" {
+			t.Errorf("SpanID = %q", tc.SpanID)
+		}
+		if tc.TraceFlags != "01" {
+			t.Errorf("TraceFlags = %q", tc.TraceFlags)
+		}
+	})
+
+	malformed := []string{
+		"",
+		"not-a-traceparent",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",          // too few segments
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", // too many segments
+		"00-zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz-00f067aa0ba902b7-01",       // non-hex trace id
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",         // wrong-length span id
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",      // all-zero trace id
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",      // all-zero span id
+	}
+	for _, h := range malformed {
+		t.Run("rejects malformed: "+h, func(t *testing.T) {
+			if _, ok := parseTraceparent(h); ok {
+				t.Errorf("expected %q to be rejected", h)
+			}
+		})
+	}
+}
+
+func TestNewTraceparent(t *testing.T) {
+	tc := newTraceparent()
+	if len(tc.TraceID) != 32 {
+		t.Errorf("TraceID length = %d, want 32", len(tc.TraceID))
+	}
+	if len(tc.SpanID) != 16 {
+		t.Errorf("SpanID length = %d, want 16", len(tc.SpanID))
+	}
+	if tc.TraceFlags != "01" {
+		t.Errorf("TraceFlags = %q, want sampled (01)", tc.TraceFlags)
+	}
+	if !isHex(tc.TraceID) || !isHex(tc.SpanID) {
+		t.Errorf("generated ids must be valid hex: trace_id=%q span_id=%q", tc.TraceID, tc.SpanID)
+	}
+	// A freshly generated id must itself be parseable as a valid traceparent.
+	if _, ok := parseTraceparent(tc.header()); !ok {
+		t.Errorf("newTraceparent().header() = %q did not round-trip through parseTraceparent", tc.header())
+	}
+}
+
+func TestTraceContextHeader(t *testing.T) {
+	tc := traceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", TraceFlags: "01"}
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got := tc.header(); got != want {
+		t.Errorf("header() = %q, want %q", got, want)
+	}
+}